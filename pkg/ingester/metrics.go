@@ -470,6 +470,8 @@ type tsdbMetrics struct {
 	// Metrics aggregated from TSDB.
 	tsdbCompactionsTotal              *prometheus.Desc
 	tsdbCompactionDuration            *prometheus.Desc
+	tsdbCompactionChunkSize           *prometheus.Desc
+	tsdbCompactionChunkSamples        *prometheus.Desc
 	tsdbFsyncDuration                 *prometheus.Desc
 	tsdbPageFlushes                   *prometheus.Desc
 	tsdbPageCompletions               *prometheus.Desc
@@ -531,6 +533,14 @@ func newTSDBMetrics(r prometheus.Registerer, logger log.Logger) *tsdbMetrics {
 			"cortex_ingester_tsdb_compaction_duration_seconds",
 			"Duration of TSDB compaction runs.",
 			nil, nil),
+		tsdbCompactionChunkSize: prometheus.NewDesc(
+			"cortex_ingester_tsdb_compaction_chunk_size_bytes",
+			"Final size of TSDB chunks on their first compaction.",
+			nil, nil),
+		tsdbCompactionChunkSamples: prometheus.NewDesc(
+			"cortex_ingester_tsdb_compaction_chunk_samples",
+			"Final number of samples in TSDB chunks on their first compaction.",
+			nil, nil),
 		tsdbFsyncDuration: prometheus.NewDesc(
 			"cortex_ingester_tsdb_wal_fsync_duration_seconds",
 			"Duration of TSDB WAL fsync.",
@@ -706,6 +716,8 @@ func newTSDBMetrics(r prometheus.Registerer, logger log.Logger) *tsdbMetrics {
 func (sm *tsdbMetrics) Describe(out chan<- *prometheus.Desc) {
 	out <- sm.tsdbCompactionsTotal
 	out <- sm.tsdbCompactionDuration
+	out <- sm.tsdbCompactionChunkSize
+	out <- sm.tsdbCompactionChunkSamples
 	out <- sm.tsdbFsyncDuration
 	out <- sm.tsdbPageFlushes
 	out <- sm.tsdbPageCompletions
@@ -755,6 +767,8 @@ func (sm *tsdbMetrics) Collect(out chan<- prometheus.Metric) {
 
 	data.SendSumOfCounters(out, sm.tsdbCompactionsTotal, "prometheus_tsdb_compactions_total")
 	data.SendSumOfHistograms(out, sm.tsdbCompactionDuration, "prometheus_tsdb_compaction_duration_seconds")
+	data.SendSumOfHistograms(out, sm.tsdbCompactionChunkSize, "prometheus_tsdb_compaction_chunk_size_bytes")
+	data.SendSumOfHistograms(out, sm.tsdbCompactionChunkSamples, "prometheus_tsdb_compaction_chunk_samples")
 	data.SendSumOfSummaries(out, sm.tsdbFsyncDuration, "prometheus_tsdb_wal_fsync_duration_seconds")
 	data.SendSumOfCounters(out, sm.tsdbPageFlushes, "prometheus_tsdb_wal_page_flushes_total")
 	data.SendSumOfCounters(out, sm.tsdbPageCompletions, "prometheus_tsdb_wal_completed_pages_total")
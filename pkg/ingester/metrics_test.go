@@ -30,6 +30,42 @@ func TestTSDBMetrics(t *testing.T) {
 			# TYPE cortex_ingester_tsdb_compactions_total counter
 			cortex_ingester_tsdb_compactions_total 693917
 
+			# HELP cortex_ingester_tsdb_compaction_chunk_samples Final number of samples in TSDB chunks on their first compaction.
+			# TYPE cortex_ingester_tsdb_compaction_chunk_samples histogram
+			cortex_ingester_tsdb_compaction_chunk_samples_bucket{le="4"} 0
+			cortex_ingester_tsdb_compaction_chunk_samples_bucket{le="6"} 0
+			cortex_ingester_tsdb_compaction_chunk_samples_bucket{le="9"} 0
+			cortex_ingester_tsdb_compaction_chunk_samples_bucket{le="13.5"} 0
+			cortex_ingester_tsdb_compaction_chunk_samples_bucket{le="20.25"} 0
+			cortex_ingester_tsdb_compaction_chunk_samples_bucket{le="30.375"} 0
+			cortex_ingester_tsdb_compaction_chunk_samples_bucket{le="45.5625"} 0
+			cortex_ingester_tsdb_compaction_chunk_samples_bucket{le="68.34375"} 0
+			cortex_ingester_tsdb_compaction_chunk_samples_bucket{le="102.515625"} 0
+			cortex_ingester_tsdb_compaction_chunk_samples_bucket{le="153.7734375"} 3
+			cortex_ingester_tsdb_compaction_chunk_samples_bucket{le="230.66015625"} 3
+			cortex_ingester_tsdb_compaction_chunk_samples_bucket{le="345.990234375"} 3
+			cortex_ingester_tsdb_compaction_chunk_samples_bucket{le="+Inf"} 3
+			cortex_ingester_tsdb_compaction_chunk_samples_sum 360
+			cortex_ingester_tsdb_compaction_chunk_samples_count 3
+
+			# HELP cortex_ingester_tsdb_compaction_chunk_size_bytes Final size of TSDB chunks on their first compaction.
+			# TYPE cortex_ingester_tsdb_compaction_chunk_size_bytes histogram
+			cortex_ingester_tsdb_compaction_chunk_size_bytes_bucket{le="32"} 0
+			cortex_ingester_tsdb_compaction_chunk_size_bytes_bucket{le="48"} 0
+			cortex_ingester_tsdb_compaction_chunk_size_bytes_bucket{le="72"} 0
+			cortex_ingester_tsdb_compaction_chunk_size_bytes_bucket{le="108"} 0
+			cortex_ingester_tsdb_compaction_chunk_size_bytes_bucket{le="162"} 3
+			cortex_ingester_tsdb_compaction_chunk_size_bytes_bucket{le="243"} 3
+			cortex_ingester_tsdb_compaction_chunk_size_bytes_bucket{le="364.5"} 3
+			cortex_ingester_tsdb_compaction_chunk_size_bytes_bucket{le="546.75"} 3
+			cortex_ingester_tsdb_compaction_chunk_size_bytes_bucket{le="820.125"} 3
+			cortex_ingester_tsdb_compaction_chunk_size_bytes_bucket{le="1230.1875"} 3
+			cortex_ingester_tsdb_compaction_chunk_size_bytes_bucket{le="1845.28125"} 3
+			cortex_ingester_tsdb_compaction_chunk_size_bytes_bucket{le="2767.921875"} 3
+			cortex_ingester_tsdb_compaction_chunk_size_bytes_bucket{le="+Inf"} 3
+			cortex_ingester_tsdb_compaction_chunk_size_bytes_sum 450
+			cortex_ingester_tsdb_compaction_chunk_size_bytes_count 3
+
 			# HELP cortex_ingester_tsdb_compaction_duration_seconds Duration of TSDB compaction runs.
 			# TYPE cortex_ingester_tsdb_compaction_duration_seconds histogram
 			cortex_ingester_tsdb_compaction_duration_seconds_bucket{le="1"} 0
@@ -258,6 +294,42 @@ func TestTSDBMetricsWithRemoval(t *testing.T) {
 			# TYPE cortex_ingester_tsdb_compactions_total counter
 			cortex_ingester_tsdb_compactions_total 693917
 
+			# HELP cortex_ingester_tsdb_compaction_chunk_samples Final number of samples in TSDB chunks on their first compaction.
+			# TYPE cortex_ingester_tsdb_compaction_chunk_samples histogram
+			cortex_ingester_tsdb_compaction_chunk_samples_bucket{le="4"} 0
+			cortex_ingester_tsdb_compaction_chunk_samples_bucket{le="6"} 0
+			cortex_ingester_tsdb_compaction_chunk_samples_bucket{le="9"} 0
+			cortex_ingester_tsdb_compaction_chunk_samples_bucket{le="13.5"} 0
+			cortex_ingester_tsdb_compaction_chunk_samples_bucket{le="20.25"} 0
+			cortex_ingester_tsdb_compaction_chunk_samples_bucket{le="30.375"} 0
+			cortex_ingester_tsdb_compaction_chunk_samples_bucket{le="45.5625"} 0
+			cortex_ingester_tsdb_compaction_chunk_samples_bucket{le="68.34375"} 0
+			cortex_ingester_tsdb_compaction_chunk_samples_bucket{le="102.515625"} 0
+			cortex_ingester_tsdb_compaction_chunk_samples_bucket{le="153.7734375"} 3
+			cortex_ingester_tsdb_compaction_chunk_samples_bucket{le="230.66015625"} 3
+			cortex_ingester_tsdb_compaction_chunk_samples_bucket{le="345.990234375"} 3
+			cortex_ingester_tsdb_compaction_chunk_samples_bucket{le="+Inf"} 3
+			cortex_ingester_tsdb_compaction_chunk_samples_sum 360
+			cortex_ingester_tsdb_compaction_chunk_samples_count 3
+
+			# HELP cortex_ingester_tsdb_compaction_chunk_size_bytes Final size of TSDB chunks on their first compaction.
+			# TYPE cortex_ingester_tsdb_compaction_chunk_size_bytes histogram
+			cortex_ingester_tsdb_compaction_chunk_size_bytes_bucket{le="32"} 0
+			cortex_ingester_tsdb_compaction_chunk_size_bytes_bucket{le="48"} 0
+			cortex_ingester_tsdb_compaction_chunk_size_bytes_bucket{le="72"} 0
+			cortex_ingester_tsdb_compaction_chunk_size_bytes_bucket{le="108"} 0
+			cortex_ingester_tsdb_compaction_chunk_size_bytes_bucket{le="162"} 3
+			cortex_ingester_tsdb_compaction_chunk_size_bytes_bucket{le="243"} 3
+			cortex_ingester_tsdb_compaction_chunk_size_bytes_bucket{le="364.5"} 3
+			cortex_ingester_tsdb_compaction_chunk_size_bytes_bucket{le="546.75"} 3
+			cortex_ingester_tsdb_compaction_chunk_size_bytes_bucket{le="820.125"} 3
+			cortex_ingester_tsdb_compaction_chunk_size_bytes_bucket{le="1230.1875"} 3
+			cortex_ingester_tsdb_compaction_chunk_size_bytes_bucket{le="1845.28125"} 3
+			cortex_ingester_tsdb_compaction_chunk_size_bytes_bucket{le="2767.921875"} 3
+			cortex_ingester_tsdb_compaction_chunk_size_bytes_bucket{le="+Inf"} 3
+			cortex_ingester_tsdb_compaction_chunk_size_bytes_sum 450
+			cortex_ingester_tsdb_compaction_chunk_size_bytes_count 3
+
 			# HELP cortex_ingester_tsdb_compaction_duration_seconds Duration of TSDB compaction runs.
 			# TYPE cortex_ingester_tsdb_compaction_duration_seconds histogram
 			cortex_ingester_tsdb_compaction_duration_seconds_bucket{le="1"} 0
@@ -493,6 +565,20 @@ func populateTSDBMetrics(base float64) *prometheus.Registry {
 	})
 	duration.Observe(9)
 
+	chunkSize := promauto.With(r).NewHistogram(prometheus.HistogramOpts{
+		Name:    "prometheus_tsdb_compaction_chunk_size_bytes",
+		Help:    "Final size of chunks on their first compaction",
+		Buckets: prometheus.ExponentialBuckets(32, 1.5, 12),
+	})
+	chunkSize.Observe(150)
+
+	chunkSamples := promauto.With(r).NewHistogram(prometheus.HistogramOpts{
+		Name:    "prometheus_tsdb_compaction_chunk_samples",
+		Help:    "Final number of samples on their first compaction",
+		Buckets: prometheus.ExponentialBuckets(4, 1.5, 12),
+	})
+	chunkSamples.Observe(120)
+
 	fsyncDuration := promauto.With(r).NewSummary(prometheus.SummaryOpts{
 		Name:       "prometheus_tsdb_wal_fsync_duration_seconds",
 		Help:       "Duration of WAL fsync.",
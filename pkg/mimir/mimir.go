@@ -31,6 +31,7 @@ import (
 	"github.com/grafana/dskit/services"
 	"github.com/grafana/dskit/signals"
 	"github.com/grafana/dskit/spanprofiler"
+	"github.com/grafana/dskit/user"
 	"github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
@@ -105,6 +106,7 @@ type Config struct {
 	Target                          flagext.StringSliceCSV `yaml:"target"`
 	MultitenancyEnabled             bool                   `yaml:"multitenancy_enabled"`
 	NoAuthTenant                    string                 `yaml:"no_auth_tenant" category:"advanced"`
+	TenantIDHeader                  string                 `yaml:"tenant_id_header" category:"experimental"`
 	ShutdownDelay                   time.Duration          `yaml:"shutdown_delay" category:"advanced"`
 	MaxSeparateMetricsGroupsPerUser int                    `yaml:"max_separate_metrics_groups_per_user" category:"experimental"`
 	EnableGoRuntimeMetrics          bool                   `yaml:"enable_go_runtime_metrics" category:"advanced"`
@@ -161,8 +163,9 @@ func (c *Config) RegisterFlags(f *flag.FlagSet, logger log.Logger) {
 		"The default value 'all' includes all components that are required to form a functional Grafana Mimir instance in single-binary mode. "+
 		"Use the '-modules' command line flag to get a list of available components, and to see which components are included with 'all'.")
 
-	f.BoolVar(&c.MultitenancyEnabled, "auth.multitenancy-enabled", true, "When set to true, incoming HTTP requests must specify tenant ID in HTTP X-Scope-OrgId header. When set to false, tenant ID from -auth.no-auth-tenant is used instead.")
+	f.BoolVar(&c.MultitenancyEnabled, "auth.multitenancy-enabled", true, "When set to true, incoming HTTP requests must specify tenant ID in the HTTP header configured by -auth.tenant-id-header, which defaults to X-Scope-OrgID. When set to false, tenant ID from -auth.no-auth-tenant is used instead.")
 	f.StringVar(&c.NoAuthTenant, "auth.no-auth-tenant", "anonymous", "Tenant ID to use when multitenancy is disabled.")
+	f.StringVar(&c.TenantIDHeader, "auth.tenant-id-header", user.OrgIDHeaderName, "Name of the HTTP header from which the tenant ID is read when multitenancy is enabled. When set to a header other than X-Scope-OrgID, the configured header is the only source of the tenant ID and any X-Scope-OrgID header sent by the client is ignored.")
 	f.BoolVar(&c.PrintConfig, "print.config", false, "Print the config and exit.")
 	f.DurationVar(&c.ShutdownDelay, "shutdown-delay", 0, "How long to wait between SIGTERM and shutdown. After receiving SIGTERM, Mimir will report not-ready status via /ready endpoint.")
 	f.IntVar(&c.MaxSeparateMetricsGroupsPerUser, "max-separate-metrics-groups-per-user", 1000, "Maximum number of groups allowed per user by which specified distributor and ingester metrics can be further separated.")
@@ -756,6 +759,7 @@ func New(cfg Config, reg prometheus.Registerer) (*Mimir, error) {
 		&cfg.Server,
 		cfg.MultitenancyEnabled,
 		cfg.NoAuthTenant,
+		cfg.TenantIDHeader,
 		// Also don't check auth for these gRPC methods, since single call is used for multiple users (or no user like health check).
 		[]string{
 			"/grpc.health.v1.Health/Check",
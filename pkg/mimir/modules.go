@@ -59,6 +59,7 @@ import (
 	"github.com/grafana/mimir/pkg/util"
 	"github.com/grafana/mimir/pkg/util/activitytracker"
 	util_log "github.com/grafana/mimir/pkg/util/log"
+	"github.com/grafana/mimir/pkg/util/noauth"
 	"github.com/grafana/mimir/pkg/util/validation"
 	"github.com/grafana/mimir/pkg/util/validation/exporter"
 	"github.com/grafana/mimir/pkg/util/version"
@@ -599,7 +600,9 @@ func (t *Mimir) initQuerier() (serv services.Service, err error) {
 		// Second, set the http.Handler that the frontend worker will use to process requests to point to
 		// the external HTTP server. This will allow the querier to consolidate query metrics both external
 		// and internal using the default instrumentation when running as a standalone service.
-		internalQuerierRouter = t.Server.HTTPServer.Handler
+		// Requests received from the frontend worker carry the tenant ID in the X-Scope-OrgID header,
+		// so they must not be subject to the custom tenant ID header configured for external requests.
+		internalQuerierRouter = noauth.InternalRequestMiddleware.Wrap(t.Server.HTTPServer.Handler)
 	} else {
 		// Monolithic mode requires a query-frontend endpoint for the worker. If no frontend and scheduler endpoint
 		// is configured, Mimir will default to using frontend on localhost on it's own gRPC listening port.
@@ -181,7 +181,15 @@ func NewClient(ctx context.Context, cfg Config, name string, logger log.Logger,
 		backendClient = NewPrefixedBucketClient(backendClient, cfg.StoragePrefix)
 	}
 
-	instrumentedClient := objstoretracing.WrapWithTraces(bucketWithMetrics(backendClient, name, reg))
+	backendClient = bucketWithMetrics(backendClient, name, reg)
+
+	// Wrap above the metrics bucket, so that errors marked as expected by the caller
+	// (e.g. object not found) are not tracked as failures.
+	if cfg.Backend == S3 && reg != nil {
+		backendClient = s3.WrapWithErrorMetrics(backendClient, prometheus.WrapRegistererWith(prometheus.Labels{"component": name}, reg))
+	}
+
+	instrumentedClient := objstoretracing.WrapWithTraces(backendClient)
 
 	// Wrap the client with any provided middleware
 	for _, wrap := range cfg.Middlewares {
@@ -0,0 +1,184 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package s3
+
+import (
+	"context"
+	"io"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/thanos-io/objstore"
+)
+
+const (
+	// unknownErrorCode is the error code used when a failure is not an S3 error response
+	// (e.g. a network error or a timeout on the client side).
+	unknownErrorCode = "unknown"
+)
+
+// errorMetricsBucket wraps an S3 objstore.Bucket and tracks failed operations by S3 error code,
+// so that S3-side throttling (e.g. SlowDown) can be told apart from other failures.
+type errorMetricsBucket struct {
+	objstore.Bucket
+
+	failures            *prometheus.CounterVec
+	isOpFailureExpected objstore.IsOpFailureExpectedFunc
+}
+
+// WrapWithErrorMetrics returns a bucket tracking failed operations against the input S3 bucket,
+// partitioned by operation and S3 error code, including the failures occurring while reading
+// the content of objects. Errors marked as expected through WithExpectedErrs
+// or ReaderWithExpectedErrs are not tracked, consistently with objstore.WrapWithMetrics.
+func WrapWithErrorMetrics(bkt objstore.Bucket, reg prometheus.Registerer) objstore.InstrumentedBucket {
+	return &errorMetricsBucket{
+		Bucket: bkt,
+		failures: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "cortex_s3_bucket_operation_failures_total",
+			Help: "Total number of operations against the S3 bucket that failed, partitioned by S3 error code.",
+		}, []string{"operation", "code"}),
+		isOpFailureExpected: func(error) bool { return false },
+	}
+}
+
+// WithExpectedErrs implements objstore.InstrumentedBucket.
+func (b *errorMetricsBucket) WithExpectedErrs(fn objstore.IsOpFailureExpectedFunc) objstore.Bucket {
+	bkt := b.Bucket
+	if ib, ok := bkt.(objstore.InstrumentedBucket); ok {
+		bkt = ib.WithExpectedErrs(fn)
+	}
+
+	return &errorMetricsBucket{
+		Bucket:              bkt,
+		failures:            b.failures,
+		isOpFailureExpected: fn,
+	}
+}
+
+// ReaderWithExpectedErrs implements objstore.InstrumentedBucket.
+func (b *errorMetricsBucket) ReaderWithExpectedErrs(fn objstore.IsOpFailureExpectedFunc) objstore.BucketReader {
+	return b.WithExpectedErrs(fn)
+}
+
+func (b *errorMetricsBucket) Iter(ctx context.Context, dir string, f func(string) error, options ...objstore.IterOption) error {
+	err := b.Bucket.Iter(ctx, dir, f, options...)
+	b.observe(ctx, objstore.OpIter, err)
+	return err
+}
+
+func (b *errorMetricsBucket) Get(ctx context.Context, name string) (io.ReadCloser, error) {
+	r, err := b.Bucket.Get(ctx, name)
+	if err != nil {
+		b.observe(ctx, objstore.OpGet, err)
+		return nil, err
+	}
+	return newErrorMetricsReader(ctx, r, objstore.OpGet, b), nil
+}
+
+func (b *errorMetricsBucket) GetRange(ctx context.Context, name string, off, length int64) (io.ReadCloser, error) {
+	r, err := b.Bucket.GetRange(ctx, name, off, length)
+	if err != nil {
+		b.observe(ctx, objstore.OpGetRange, err)
+		return nil, err
+	}
+	return newErrorMetricsReader(ctx, r, objstore.OpGetRange, b), nil
+}
+
+func (b *errorMetricsBucket) Exists(ctx context.Context, name string) (bool, error) {
+	exists, err := b.Bucket.Exists(ctx, name)
+	b.observe(ctx, objstore.OpExists, err)
+	return exists, err
+}
+
+func (b *errorMetricsBucket) Attributes(ctx context.Context, name string) (objstore.ObjectAttributes, error) {
+	attrs, err := b.Bucket.Attributes(ctx, name)
+	b.observe(ctx, objstore.OpAttributes, err)
+	return attrs, err
+}
+
+func (b *errorMetricsBucket) Upload(ctx context.Context, name string, r io.Reader) error {
+	err := b.Bucket.Upload(ctx, name, r)
+	b.observe(ctx, objstore.OpUpload, err)
+	return err
+}
+
+func (b *errorMetricsBucket) Delete(ctx context.Context, name string) error {
+	err := b.Bucket.Delete(ctx, name)
+	b.observe(ctx, objstore.OpDelete, err)
+	return err
+}
+
+func (b *errorMetricsBucket) observe(ctx context.Context, op string, err error) {
+	// Errors expected by the caller and requests canceled by the caller are not a failure of the S3 service.
+	if err == nil || b.isOpFailureExpected(err) || errors.Is(ctx.Err(), context.Canceled) {
+		return
+	}
+
+	code := unknownErrorCode
+	var errResp minio.ErrorResponse
+	if errors.As(err, &errResp) && errResp.Code != "" {
+		code = errResp.Code
+	}
+
+	b.failures.WithLabelValues(op, code).Inc()
+}
+
+// errorMetricsReader tracks the failures occurring while reading an object, after it has been
+// successfully opened (e.g. a timeout or a connection reset while reading a large object).
+type errorMetricsReader struct {
+	io.ReadCloser
+
+	ctx    context.Context
+	op     string
+	bucket *errorMetricsBucket
+
+	objSize    int64
+	objSizeErr error
+
+	// alreadyGotErr is used to track at most one failure per reader.
+	alreadyGotErr bool
+}
+
+func newErrorMetricsReader(ctx context.Context, r io.ReadCloser, op string, bucket *errorMetricsBucket) io.ReadCloser {
+	objSize, objSizeErr := objstore.TryToGetSize(r)
+
+	return &errorMetricsReader{
+		ReadCloser: r,
+		ctx:        ctx,
+		op:         op,
+		bucket:     bucket,
+		objSize:    objSize,
+		objSizeErr: objSizeErr,
+	}
+}
+
+// ObjectSize implements objstore.ObjectSizer.
+func (r *errorMetricsReader) ObjectSize() (int64, error) {
+	return r.objSize, r.objSizeErr
+}
+
+func (r *errorMetricsReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if err != nil && err != io.EOF {
+		r.observe(err)
+	}
+	return n, err
+}
+
+func (r *errorMetricsReader) Close() error {
+	err := r.ReadCloser.Close()
+	if err != nil {
+		r.observe(err)
+	}
+	return err
+}
+
+func (r *errorMetricsReader) observe(err error) {
+	if r.alreadyGotErr {
+		return
+	}
+	r.alreadyGotErr = true
+	r.bucket.observe(r.ctx, r.op, err)
+}
@@ -0,0 +1,181 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package s3
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/minio/minio-go/v7"
+	pkgerrors "github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thanos-io/objstore"
+)
+
+func TestWrapWithErrorMetrics(t *testing.T) {
+	inner := &failingBucket{Bucket: objstore.NewInMemBucket()}
+	reg := prometheus.NewPedanticRegistry()
+	bkt := WrapWithErrorMetrics(inner, reg)
+	ctx := context.Background()
+
+	// Successful operations should not be tracked.
+	require.NoError(t, bkt.Upload(ctx, "object", bytes.NewReader([]byte("content"))))
+	_, err := bkt.Exists(ctx, "object")
+	require.NoError(t, err)
+
+	// S3 error responses should be tracked by their error code, even when wrapped.
+	inner.err = minio.ErrorResponse{Code: "SlowDown"}
+	require.Error(t, bkt.Upload(ctx, "object", bytes.NewReader([]byte("content"))))
+	_, err = bkt.Get(ctx, "object")
+	require.Error(t, err)
+
+	inner.err = pkgerrors.Wrap(minio.ErrorResponse{Code: "NoSuchKey"}, "get object")
+	_, err = bkt.Get(ctx, "object")
+	require.Error(t, err)
+
+	inner.err = fmt.Errorf("get object range: %w", minio.ErrorResponse{Code: "SlowDown"})
+	_, err = bkt.GetRange(ctx, "object", 0, 1)
+	require.Error(t, err)
+
+	// Errors occurring while reading the content of an object should be tracked once per reader.
+	inner.err = nil
+	inner.readErr = minio.ErrorResponse{Code: "RequestTimeout"}
+	r, err := bkt.GetRange(ctx, "object", 0, 1)
+	require.NoError(t, err)
+	_, err = io.ReadAll(r)
+	require.Error(t, err)
+	_, err = r.Read(make([]byte, 1))
+	require.Error(t, err)
+	require.NoError(t, r.Close())
+
+	// Successfully reading an object should not be tracked.
+	inner.readErr = nil
+	r, err = bkt.Get(ctx, "object")
+	require.NoError(t, err)
+	_, err = io.ReadAll(r)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+
+	// Errors which are not S3 error responses should be tracked as unknown.
+	inner.err = errors.New("connection reset by peer")
+	require.Error(t, bkt.Delete(ctx, "object"))
+
+	// Operations canceled by the caller should not be tracked.
+	canceledCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	require.Error(t, bkt.Delete(canceledCtx, "object"))
+
+	assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+		# HELP cortex_s3_bucket_operation_failures_total Total number of operations against the S3 bucket that failed, partitioned by S3 error code.
+		# TYPE cortex_s3_bucket_operation_failures_total counter
+		cortex_s3_bucket_operation_failures_total{code="NoSuchKey",operation="get"} 1
+		cortex_s3_bucket_operation_failures_total{code="RequestTimeout",operation="get_range"} 1
+		cortex_s3_bucket_operation_failures_total{code="SlowDown",operation="get"} 1
+		cortex_s3_bucket_operation_failures_total{code="SlowDown",operation="get_range"} 1
+		cortex_s3_bucket_operation_failures_total{code="SlowDown",operation="upload"} 1
+		cortex_s3_bucket_operation_failures_total{code="unknown",operation="delete"} 1
+	`), "cortex_s3_bucket_operation_failures_total"))
+}
+
+func TestWrapWithErrorMetrics_ExpectedErrors(t *testing.T) {
+	inner := &failingBucket{Bucket: objstore.NewInMemBucket(), err: minio.ErrorResponse{Code: "NoSuchKey"}}
+	reg := prometheus.NewPedanticRegistry()
+	bkt := WrapWithErrorMetrics(objstore.WrapWithMetrics(inner, prometheus.WrapRegistererWithPrefix("thanos_", reg), ""), reg)
+	ctx := context.Background()
+
+	isNotFound := func(err error) bool {
+		return minio.ToErrorResponse(pkgerrors.Cause(err)).Code == "NoSuchKey"
+	}
+
+	// Errors expected by the caller should not be tracked as failures, neither by this
+	// bucket nor by the wrapped instrumented bucket.
+	_, err := bkt.WithExpectedErrs(isNotFound).Get(ctx, "object")
+	require.Error(t, err)
+	_, err = bkt.ReaderWithExpectedErrs(isNotFound).Get(ctx, "object")
+	require.Error(t, err)
+
+	// Unexpected errors should still be tracked.
+	_, err = bkt.Get(ctx, "object")
+	require.Error(t, err)
+
+	assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+		# HELP cortex_s3_bucket_operation_failures_total Total number of operations against the S3 bucket that failed, partitioned by S3 error code.
+		# TYPE cortex_s3_bucket_operation_failures_total counter
+		cortex_s3_bucket_operation_failures_total{code="NoSuchKey",operation="get"} 1
+	`), "cortex_s3_bucket_operation_failures_total"))
+	assert.Equal(t, float64(1), objstoreGetFailures(t, reg))
+}
+
+// objstoreGetFailures returns the number of failed get operations tracked by objstore.WrapWithMetrics.
+func objstoreGetFailures(t *testing.T, reg prometheus.Gatherer) float64 {
+	families, err := reg.Gather()
+	require.NoError(t, err)
+
+	for _, family := range families {
+		if family.GetName() != "thanos_objstore_bucket_operation_failures_total" {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			for _, l := range m.GetLabel() {
+				if l.GetName() == "operation" && l.GetValue() == objstore.OpGet {
+					return m.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+
+	require.FailNow(t, "get failures metric not found")
+	return 0
+}
+
+// failingBucket is an objstore.Bucket returning the configured error, if any, on each operation,
+// and the configured read error, if any, when reading the content of objects.
+type failingBucket struct {
+	objstore.Bucket
+
+	err     error
+	readErr error
+}
+
+func (b *failingBucket) Get(ctx context.Context, name string) (io.ReadCloser, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if b.readErr != nil {
+		return io.NopCloser(iotest.ErrReader(b.readErr)), nil
+	}
+	return b.Bucket.Get(ctx, name)
+}
+
+func (b *failingBucket) GetRange(ctx context.Context, name string, off, length int64) (io.ReadCloser, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if b.readErr != nil {
+		return io.NopCloser(iotest.ErrReader(b.readErr)), nil
+	}
+	return b.Bucket.GetRange(ctx, name, off, length)
+}
+
+func (b *failingBucket) Upload(ctx context.Context, name string, r io.Reader) error {
+	if b.err != nil {
+		return b.err
+	}
+	return b.Bucket.Upload(ctx, name, r)
+}
+
+func (b *failingBucket) Delete(ctx context.Context, name string) error {
+	if b.err != nil {
+		return b.err
+	}
+	return b.Bucket.Delete(ctx, name)
+}
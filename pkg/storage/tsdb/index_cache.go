@@ -40,7 +40,8 @@ const (
 var (
 	supportedIndexCacheBackends = []string{IndexCacheBackendInMemory, IndexCacheBackendMemcached, IndexCacheBackendRedis}
 
-	errUnsupportedIndexCacheBackend = errors.New("unsupported index cache backend")
+	errUnsupportedIndexCacheBackend        = errors.New("unsupported index cache backend")
+	errInvalidIndexCacheMaxTenantSizeRatio = errors.New("the in-memory index cache max tenant size ratio must be between 0 and 1")
 )
 
 type IndexCacheConfig struct {
@@ -73,15 +74,30 @@ func (cfg *IndexCacheConfig) Validate() error {
 		}
 	}
 
+	if cfg.Backend == IndexCacheBackendInMemory {
+		if err := cfg.InMemory.Validate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
 type InMemoryIndexCacheConfig struct {
-	MaxSizeBytes uint64 `yaml:"max_size_bytes"`
+	MaxSizeBytes       uint64  `yaml:"max_size_bytes"`
+	MaxTenantSizeRatio float64 `yaml:"max_tenant_size_ratio" category:"experimental"`
 }
 
 func (cfg *InMemoryIndexCacheConfig) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
 	f.Uint64Var(&cfg.MaxSizeBytes, prefix+"max-size-bytes", uint64(1*units.Gibibyte), "Maximum size in bytes of in-memory index cache used to speed up blocks index lookups (shared between all tenants).")
+	f.Float64Var(&cfg.MaxTenantSizeRatio, prefix+"max-tenant-size-ratio", 0, "Maximum fraction of the in-memory index cache size that a single tenant can occupy, in the range (0, 1]. When a tenant exceeds its share, its own least recently used items are evicted. Enabling this limit adds a per-item memory overhead to track the items of each tenant, which is not accounted in the max size. 0 to disable.")
+}
+
+func (cfg *InMemoryIndexCacheConfig) Validate() error {
+	if cfg.MaxTenantSizeRatio < 0 || cfg.MaxTenantSizeRatio > 1 {
+		return errInvalidIndexCacheMaxTenantSizeRatio
+	}
+	return nil
 }
 
 // NewIndexCache creates a new index cache based on the input configuration.
@@ -108,8 +124,9 @@ func newInMemoryIndexCache(cfg InMemoryIndexCacheConfig, logger log.Logger, regi
 	}
 
 	return indexcache.NewInMemoryIndexCacheWithConfig(logger, registerer, indexcache.InMemoryIndexCacheConfig{
-		MaxSize:     maxCacheSize,
-		MaxItemSize: maxItemSize,
+		MaxSize:            maxCacheSize,
+		MaxItemSize:        maxItemSize,
+		MaxTenantSizeRatio: cfg.MaxTenantSizeRatio,
 	})
 }
 
@@ -90,6 +90,29 @@ func TestIndexCacheConfig_Validate(t *testing.T) {
 				return cfg
 			}(),
 		},
+		"inmemory with valid max tenant size ratio should pass": {
+			cfg: func() IndexCacheConfig {
+				cfg := IndexCacheConfig{}
+				flagext.DefaultValues(&cfg)
+
+				cfg.Backend = IndexCacheBackendInMemory
+				cfg.InMemory.MaxTenantSizeRatio = 0.5
+
+				return cfg
+			}(),
+		},
+		"inmemory with max tenant size ratio greater than 1 should fail": {
+			cfg: func() IndexCacheConfig {
+				cfg := IndexCacheConfig{}
+				flagext.DefaultValues(&cfg)
+
+				cfg.Backend = IndexCacheBackendInMemory
+				cfg.InMemory.MaxTenantSizeRatio = 1.5
+
+				return cfg
+			}(),
+			expected: errInvalidIndexCacheMaxTenantSizeRatio,
+		},
 	}
 
 	for testName, testData := range tests {
@@ -43,12 +43,20 @@ var ulidSize = uint64(len(ulid.ULID{}))
 type InMemoryIndexCache struct {
 	mtx sync.Mutex
 
-	logger           log.Logger
-	lru              *lru.LRU[cacheKey, []byte]
-	maxSizeBytes     uint64
-	maxItemSizeBytes uint64
+	logger             log.Logger
+	lru                *lru.LRU[cacheKey, []byte]
+	maxSizeBytes       uint64
+	maxItemSizeBytes   uint64
+	maxTenantSizeBytes uint64
 
-	curSize uint64
+	curSize         uint64
+	curSizeByTenant map[string]uint64
+
+	// lruByTenant tracks the recency of each tenant's keys, so that a tenant exceeding
+	// its share of the cache can evict its own oldest items. Only populated when
+	// a per-tenant limit is configured. The memory used to track the keys is not
+	// accounted in curSize.
+	lruByTenant map[string]*lru.LRU[cacheKey, struct{}]
 
 	evicted          *prometheus.CounterVec
 	requests         *prometheus.CounterVec
@@ -58,6 +66,7 @@ type InMemoryIndexCache struct {
 	currentSize      *prometheus.GaugeVec
 	totalCurrentSize *prometheus.GaugeVec
 	overflow         *prometheus.CounterVec
+	tenantOverflow   *prometheus.CounterVec
 }
 
 // InMemoryIndexCacheConfig holds the in-memory index cache config.
@@ -66,6 +75,9 @@ type InMemoryIndexCacheConfig struct {
 	MaxSize flagext.Bytes `yaml:"max_size"`
 	// MaxItemSize represents maximum size of single item.
 	MaxItemSize flagext.Bytes `yaml:"max_item_size"`
+	// MaxTenantSizeRatio represents the maximum fraction of MaxSize a single tenant can occupy.
+	// A tenant exceeding its share evicts its own least recently used items. 0 means no per-tenant limit.
+	MaxTenantSizeRatio float64 `yaml:"max_tenant_size_ratio"`
 }
 
 // parseInMemoryIndexCacheConfig unmarshals a buffer into a InMemoryIndexCacheConfig with default values.
@@ -95,11 +107,21 @@ func NewInMemoryIndexCacheWithConfig(logger log.Logger, reg prometheus.Registere
 	if config.MaxItemSize > config.MaxSize {
 		return nil, errors.Errorf("max item size (%v) cannot be bigger than overall cache size (%v)", config.MaxItemSize, config.MaxSize)
 	}
+	if config.MaxTenantSizeRatio < 0 || config.MaxTenantSizeRatio > 1 {
+		return nil, errors.Errorf("max tenant size ratio (%v) must be between 0 and 1", config.MaxTenantSizeRatio)
+	}
+	maxTenantSizeBytes := uint64(config.MaxTenantSizeRatio * float64(config.MaxSize))
+	if config.MaxTenantSizeRatio > 0 && maxTenantSizeBytes == 0 {
+		return nil, errors.Errorf("max tenant size ratio (%v) is too small for the overall cache size (%v)", config.MaxTenantSizeRatio, config.MaxSize)
+	}
 
 	c := &InMemoryIndexCache{
-		logger:           logger,
-		maxSizeBytes:     uint64(config.MaxSize),
-		maxItemSizeBytes: uint64(config.MaxItemSize),
+		logger:             logger,
+		maxSizeBytes:       uint64(config.MaxSize),
+		maxItemSizeBytes:   uint64(config.MaxItemSize),
+		maxTenantSizeBytes: maxTenantSizeBytes,
+		curSizeByTenant:    map[string]uint64{},
+		lruByTenant:        map[string]*lru.LRU[cacheKey, struct{}]{},
 	}
 
 	c.evicted = promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
@@ -126,6 +148,12 @@ func NewInMemoryIndexCacheWithConfig(logger log.Logger, reg prometheus.Registere
 	}, []string{"item_type"})
 	initLabelValuesForAllCacheTypes(c.overflow.MetricVec)
 
+	c.tenantOverflow = promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+		Name: "thanos_store_index_cache_items_tenant_overflowed_total",
+		Help: "Total number of items that could not be added to the cache due to being bigger than the tenant's share of the cache size.",
+	}, []string{"item_type"})
+	initLabelValuesForAllCacheTypes(c.tenantOverflow.MetricVec)
+
 	c.hits = promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
 		Name: "thanos_store_index_cache_hits_total",
 		Help: "Total number of requests to the cache that were a hit.",
@@ -162,6 +190,12 @@ func NewInMemoryIndexCacheWithConfig(logger log.Logger, reg prometheus.Registere
 	}, func() float64 {
 		return float64(c.maxItemSizeBytes)
 	})
+	_ = promauto.With(reg).NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "thanos_store_index_cache_max_tenant_size_bytes",
+		Help: "Maximum number of bytes a single tenant can hold in the index cache. 0 means no per-tenant limit.",
+	}, func() float64 {
+		return float64(c.maxTenantSizeBytes)
+	})
 
 	// Initialize LRU cache with a high size limit since we will manage evictions ourselves
 	// based on stored size using `RemoveOldest` method.
@@ -175,6 +209,7 @@ func NewInMemoryIndexCacheWithConfig(logger log.Logger, reg prometheus.Registere
 		"msg", "created in-memory index cache",
 		"maxItemSizeBytes", c.maxItemSizeBytes,
 		"maxSizeBytes", c.maxSizeBytes,
+		"maxTenantSizeBytes", c.maxTenantSizeBytes,
 		"maxItems", "maxInt",
 	)
 	return c, nil
@@ -190,6 +225,8 @@ func (c *InMemoryIndexCache) onEvict(key cacheKey, val []byte) {
 	c.totalCurrentSize.WithLabelValues(typ).Sub(float64(entrySize + key.size()))
 
 	c.curSize -= entrySize
+	c.subTenantSize(key.tenant(), entrySize)
+	c.removeTenantKey(key)
 }
 
 func (c *InMemoryIndexCache) get(key cacheKey) ([]byte, bool) {
@@ -203,6 +240,7 @@ func (c *InMemoryIndexCache) get(key cacheKey) ([]byte, bool) {
 	if !ok {
 		return nil, false
 	}
+	c.touchTenantKey(key)
 	c.hits.WithLabelValues(typ).Inc()
 	return v, true
 }
@@ -215,14 +253,24 @@ func (c *InMemoryIndexCache) set(key cacheKey, val []byte) {
 	defer c.mtx.Unlock()
 
 	if _, ok := c.lru.Get(key); ok {
+		c.touchTenantKey(key)
 		return
 	}
 
-	if !c.ensureFits(size, typ) {
+	// Check the max item size before making room for the tenant, so that an item which
+	// can't be cached anyway doesn't evict any of the tenant's items.
+	if !c.fitsMaxItemSize(size, typ) {
 		c.overflow.WithLabelValues(typ).Inc()
 		return
 	}
 
+	if !c.ensureTenantFits(key.tenant(), size) {
+		c.tenantOverflow.WithLabelValues(typ).Inc()
+		return
+	}
+
+	c.ensureFits(size, typ)
+
 	// The caller may be passing in a sub-slice of a huge array. Copy the data
 	// to ensure we don't waste huge amounts of space for something small.
 	v := make([]byte, len(val))
@@ -234,11 +282,77 @@ func (c *InMemoryIndexCache) set(key cacheKey, val []byte) {
 	c.totalCurrentSize.WithLabelValues(typ).Add(float64(size + key.size()))
 	c.current.WithLabelValues(typ).Inc()
 	c.curSize += size
+	c.curSizeByTenant[key.tenant()] += size
+	c.addTenantKey(key)
+}
+
+// ensureTenantFits tries to make sure that an item of the given size can be admitted to the
+// cache without the tenant exceeding its share of the cache size, evicting the tenant's own
+// oldest items if needed. Returns false if the item is bigger than the tenant's whole share.
+func (c *InMemoryIndexCache) ensureTenantFits(tenant string, size uint64) bool {
+	if c.maxTenantSizeBytes == 0 {
+		return true
+	}
+	if size > c.maxTenantSizeBytes {
+		return false
+	}
+
+	for c.curSizeByTenant[tenant]+size > c.maxTenantSizeBytes {
+		keys, ok := c.lruByTenant[tenant]
+		if !ok {
+			break
+		}
+		oldest, _, ok := keys.GetOldest()
+		if !ok {
+			break
+		}
+		// Removing the key from the cache calls onEvict, which updates the tenant's size
+		// and removes the key from the tenant's LRU.
+		c.lru.Remove(oldest)
+	}
+	return true
+}
+
+func (c *InMemoryIndexCache) addTenantKey(key cacheKey) {
+	if c.maxTenantSizeBytes == 0 {
+		return
+	}
+	keys, ok := c.lruByTenant[key.tenant()]
+	if !ok {
+		// The size limit is enforced by the cache itself, so the LRU is never full.
+		keys, _ = lru.NewLRU[cacheKey, struct{}](maxInt, nil)
+		c.lruByTenant[key.tenant()] = keys
+	}
+	keys.Add(key, struct{}{})
 }
 
-// ensureFits tries to make sure that the passed slice will fit into the LRU cache.
-// Returns true if it will fit.
-func (c *InMemoryIndexCache) ensureFits(size uint64, typ string) bool {
+func (c *InMemoryIndexCache) touchTenantKey(key cacheKey) {
+	if keys, ok := c.lruByTenant[key.tenant()]; ok {
+		keys.Get(key)
+	}
+}
+
+func (c *InMemoryIndexCache) removeTenantKey(key cacheKey) {
+	keys, ok := c.lruByTenant[key.tenant()]
+	if !ok {
+		return
+	}
+	keys.Remove(key)
+	if keys.Len() == 0 {
+		delete(c.lruByTenant, key.tenant())
+	}
+}
+
+func (c *InMemoryIndexCache) subTenantSize(tenant string, size uint64) {
+	if c.curSizeByTenant[tenant] <= size {
+		delete(c.curSizeByTenant, tenant)
+		return
+	}
+	c.curSizeByTenant[tenant] -= size
+}
+
+// fitsMaxItemSize returns whether an item of the given size is not bigger than the max item size.
+func (c *InMemoryIndexCache) fitsMaxItemSize(size uint64, typ string) bool {
 	if size > c.maxItemSizeBytes {
 		level.Debug(c.logger).Log(
 			"msg", "item bigger than maxItemSizeBytes. Ignoring..",
@@ -250,7 +364,12 @@ func (c *InMemoryIndexCache) ensureFits(size uint64, typ string) bool {
 		)
 		return false
 	}
+	return true
+}
 
+// ensureFits makes sure that the passed slice will fit into the LRU cache, evicting the
+// oldest items if needed. The item must not be bigger than the max item size.
+func (c *InMemoryIndexCache) ensureFits(size uint64, typ string) {
 	for c.curSize+size > c.maxSizeBytes {
 		if _, _, ok := c.lru.RemoveOldest(); !ok {
 			level.Error(c.logger).Log(
@@ -264,7 +383,6 @@ func (c *InMemoryIndexCache) ensureFits(size uint64, typ string) bool {
 			c.reset()
 		}
 	}
-	return true
 }
 
 func (c *InMemoryIndexCache) reset() {
@@ -273,6 +391,8 @@ func (c *InMemoryIndexCache) reset() {
 	c.currentSize.Reset()
 	c.totalCurrentSize.Reset()
 	c.curSize = 0
+	clear(c.curSizeByTenant)
+	clear(c.lruByTenant)
 }
 
 func copyString(s string) string {
@@ -382,6 +502,8 @@ type cacheKey interface {
 	typ() string
 	// size is used to keep track of the cache size, it represents the footprint of the cache key in memory.
 	size() uint64
+	// tenant is used to keep track of the cache size occupied by each tenant.
+	tenant() string
 }
 
 // cacheKeyPostings implements cacheKey and is used to reference a postings cache entry in the inmemory cache.
@@ -397,6 +519,8 @@ func (c cacheKeyPostings) size() uint64 {
 	return stringSize(c.userID) + ulidSize + stringSize(c.label.Name) + stringSize(c.label.Value)
 }
 
+func (c cacheKeyPostings) tenant() string { return c.userID }
+
 // cacheKeyPostings implements cacheKey and is used to reference a seriesRef cache entry in the inmemory cache.
 type cacheKeySeriesForRef struct {
 	userID string
@@ -410,6 +534,8 @@ func (c cacheKeySeriesForRef) size() uint64 {
 	return stringSize(c.userID) + ulidSize + 8
 }
 
+func (c cacheKeySeriesForRef) tenant() string { return c.userID }
+
 // cacheKeyPostings implements cacheKey and is used to reference an expanded postings cache entry in the inmemory cache.
 type cacheKeyExpandedPostings struct {
 	userID                    string
@@ -424,6 +550,8 @@ func (c cacheKeyExpandedPostings) size() uint64 {
 	return stringSize(c.userID) + ulidSize + stringSize(string(c.matchersKey)) + stringSize(c.postingsSelectionStrategy)
 }
 
+func (c cacheKeyExpandedPostings) tenant() string { return c.userID }
+
 type cacheKeySeriesForPostings struct {
 	userID      string
 	block       ulid.ULID
@@ -439,6 +567,8 @@ func (c cacheKeySeriesForPostings) size() uint64 {
 	return stringSize(c.userID) + ulidSize + stringSize(c.shard) + stringSize(string(c.postingsKey))
 }
 
+func (c cacheKeySeriesForPostings) tenant() string { return c.userID }
+
 type cacheKeyLabelNames struct {
 	userID      string
 	block       ulid.ULID
@@ -453,6 +583,8 @@ func (c cacheKeyLabelNames) size() uint64 {
 	return stringSize(c.userID) + ulidSize + stringSize(string(c.matchersKey))
 }
 
+func (c cacheKeyLabelNames) tenant() string { return c.userID }
+
 type cacheKeyLabelValues struct {
 	userID      string
 	block       ulid.ULID
@@ -468,6 +600,8 @@ func (c cacheKeyLabelValues) size() uint64 {
 	return stringSize(c.userID) + ulidSize + stringSize(c.labelName) + stringSize(string(c.matchersKey))
 }
 
+func (c cacheKeyLabelValues) tenant() string { return c.userID }
+
 func stringSize(s string) uint64 {
 	return stringHeaderSize + uint64(len(s))
 }
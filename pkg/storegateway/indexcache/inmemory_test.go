@@ -55,6 +55,26 @@ max_item_size: 2KB
 	conf = []byte(`
 max_size: 2KB
 max_item_size: 1MB
+`)
+	cache, err = NewInMemoryIndexCache(log.NewNopLogger(), nil, conf)
+	assert.Error(t, err)
+	assert.Equal(t, (*InMemoryIndexCache)(nil), cache)
+
+	// Should return error on invalid max tenant size ratio.
+	conf = []byte(`
+max_size: 1MB
+max_item_size: 2KB
+max_tenant_size_ratio: 2
+`)
+	cache, err = NewInMemoryIndexCache(log.NewNopLogger(), nil, conf)
+	assert.Error(t, err)
+	assert.Equal(t, (*InMemoryIndexCache)(nil), cache)
+
+	// Should return error on a max tenant size ratio resulting in a zero bytes limit.
+	conf = []byte(`
+max_size: 100B
+max_item_size: 10B
+max_tenant_size_ratio: 0.001
 `)
 	cache, err = NewInMemoryIndexCache(log.NewNopLogger(), nil, conf)
 	assert.Error(t, err)
@@ -455,6 +475,93 @@ func TestInMemoryIndexCache_Eviction_WithMetrics(t *testing.T) {
 	assert.Equal(t, float64(1), promtest.ToFloat64(cache.hits.WithLabelValues(cacheTypeSeriesForRef)))
 }
 
+func TestInMemoryIndexCache_MaxTenantSize(t *testing.T) {
+	const itemSize = sliceHeaderSize + 2
+
+	metrics := prometheus.NewRegistry()
+	cache, err := NewInMemoryIndexCacheWithConfig(log.NewNopLogger(), metrics, InMemoryIndexCacheConfig{
+		MaxItemSize:        4 * itemSize,
+		MaxSize:            4 * itemSize,
+		MaxTenantSizeRatio: 0.5,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2*itemSize), cache.maxTenantSizeBytes)
+
+	id := ulid.MustNew(0, nil)
+	ctx := context.Background()
+	lbls1 := labels.Label{Name: "test", Value: "1"}
+	lbls2 := labels.Label{Name: "test", Value: "2"}
+	lbls3 := labels.Label{Name: "test", Value: "3"}
+	lbls4 := labels.Label{Name: "test", Value: "4"}
+
+	// The first tenant can fill up to its share of the cache.
+	cache.StorePostings("user-1", id, lbls1, []byte{1, 1}, time.Hour)
+	cache.StorePostings("user-1", id, lbls2, []byte{2, 2}, time.Hour)
+	assert.Equal(t, uint64(2*itemSize), cache.curSizeByTenant["user-1"])
+	assert.Equal(t, float64(0), promtest.ToFloat64(cache.evicted.WithLabelValues(cacheTypePostings)))
+
+	// Other tenants can use the rest of the cache.
+	cache.StorePostings("user-2", id, lbls1, []byte{1, 1}, time.Hour)
+	assert.Equal(t, uint64(itemSize), cache.curSizeByTenant["user-2"])
+	assert.Equal(t, uint64(3*itemSize), cache.curSize)
+
+	// Further items of the first tenant evict its own oldest items, even if the whole
+	// cache is not full.
+	cache.StorePostings("user-1", id, lbls3, []byte{3, 3}, time.Hour)
+	assert.Equal(t, uint64(2*itemSize), cache.curSizeByTenant["user-1"])
+	assert.Equal(t, uint64(3*itemSize), cache.curSize)
+	assert.Equal(t, float64(1), promtest.ToFloat64(cache.evicted.WithLabelValues(cacheTypePostings)))
+	testFetchMultiPostings(ctx, t, cache, "user-1", id, []labels.Label{lbls1, lbls2, lbls3}, map[labels.Label][]byte{lbls2: {2, 2}, lbls3: {3, 3}})
+
+	// Fetching an item makes it the most recently used one of the tenant, so the next
+	// item evicts the least recently used one instead.
+	testFetchMultiPostings(ctx, t, cache, "user-1", id, []labels.Label{lbls2}, map[labels.Label][]byte{lbls2: {2, 2}})
+	cache.StorePostings("user-1", id, lbls4, []byte{4, 4}, time.Hour)
+	assert.Equal(t, uint64(2*itemSize), cache.curSizeByTenant["user-1"])
+	assert.Equal(t, float64(2), promtest.ToFloat64(cache.evicted.WithLabelValues(cacheTypePostings)))
+	testFetchMultiPostings(ctx, t, cache, "user-1", id, []labels.Label{lbls1, lbls2, lbls3, lbls4}, map[labels.Label][]byte{lbls2: {2, 2}, lbls4: {4, 4}})
+
+	// Other tenants are not affected.
+	testFetchMultiPostings(ctx, t, cache, "user-2", id, []labels.Label{lbls1}, map[labels.Label][]byte{lbls1: {1, 1}})
+
+	// Items bigger than the tenant's whole share are not admitted, and don't evict anything.
+	cache.StorePostings("user-1", id, lbls1, make([]byte, 2*itemSize-sliceHeaderSize+1), time.Hour)
+	assert.Equal(t, float64(1), promtest.ToFloat64(cache.tenantOverflow.WithLabelValues(cacheTypePostings)))
+	assert.Equal(t, float64(2), promtest.ToFloat64(cache.evicted.WithLabelValues(cacheTypePostings)))
+	testFetchMultiPostings(ctx, t, cache, "user-1", id, []labels.Label{lbls1, lbls2, lbls4}, map[labels.Label][]byte{lbls2: {2, 2}, lbls4: {4, 4}})
+}
+
+func TestInMemoryIndexCache_MaxTenantSize_ItemBiggerThanMaxItemSize(t *testing.T) {
+	const itemSize = sliceHeaderSize + 2
+
+	metrics := prometheus.NewRegistry()
+	cache, err := NewInMemoryIndexCacheWithConfig(log.NewNopLogger(), metrics, InMemoryIndexCacheConfig{
+		MaxItemSize:        itemSize,
+		MaxSize:            4 * itemSize,
+		MaxTenantSizeRatio: 0.5,
+	})
+	assert.NoError(t, err)
+
+	id := ulid.MustNew(0, nil)
+	ctx := context.Background()
+	lbls1 := labels.Label{Name: "test", Value: "1"}
+	lbls2 := labels.Label{Name: "test", Value: "2"}
+	lbls3 := labels.Label{Name: "test", Value: "3"}
+
+	cache.StorePostings("user-1", id, lbls1, []byte{1, 1}, time.Hour)
+	cache.StorePostings("user-1", id, lbls2, []byte{2, 2}, time.Hour)
+	assert.Equal(t, uint64(2*itemSize), cache.curSizeByTenant["user-1"])
+
+	// An item fitting the tenant's share but bigger than the max item size is not admitted,
+	// is tracked as an overflow, and doesn't evict any of the tenant's items.
+	cache.StorePostings("user-1", id, lbls3, []byte{3, 3, 3}, time.Hour)
+	assert.Equal(t, float64(1), promtest.ToFloat64(cache.overflow.WithLabelValues(cacheTypePostings)))
+	assert.Equal(t, float64(0), promtest.ToFloat64(cache.tenantOverflow.WithLabelValues(cacheTypePostings)))
+	assert.Equal(t, float64(0), promtest.ToFloat64(cache.evicted.WithLabelValues(cacheTypePostings)))
+	assert.Equal(t, uint64(2*itemSize), cache.curSizeByTenant["user-1"])
+	testFetchMultiPostings(ctx, t, cache, "user-1", id, []labels.Label{lbls1, lbls2, lbls3}, map[labels.Label][]byte{lbls1: {1, 1}, lbls2: {2, 2}})
+}
+
 func testFetchMultiPostings(ctx context.Context, t *testing.T, cache IndexCache, user string, id ulid.ULID, keys []labels.Label, expectedHits map[labels.Label][]byte) {
 	t.Helper()
 	pHits := cache.FetchMultiPostings(ctx, user, id, keys)
@@ -18,7 +18,14 @@ import (
 )
 
 // SetupAuthMiddleware for the given server config.
-func SetupAuthMiddleware(config *server.Config, multitenancyEnabled bool, noMultitenancyTenant string, noGRPCAuthOn []string) middleware.Interface {
+//
+// When multitenancy is enabled and a tenant ID header other than X-Scope-OrgID is configured,
+// the custom header is only honored for requests received by the external HTTP server: the
+// rewrite is registered as a server HTTP middleware, while the returned middleware reads the
+// tenant ID from X-Scope-OrgID. This way, requests between Mimir components (e.g. from the
+// query-frontend to the querier, or from the ruler to the query-frontend), which propagate the
+// tenant ID using X-Scope-OrgID, keep working.
+func SetupAuthMiddleware(config *server.Config, multitenancyEnabled bool, noMultitenancyTenant, tenantIDHeader string, noGRPCAuthOn []string) middleware.Interface {
 	if multitenancyEnabled {
 		ignoredMethods := map[string]bool{}
 		for _, m := range noGRPCAuthOn {
@@ -41,6 +48,9 @@ func SetupAuthMiddleware(config *server.Config, multitenancyEnabled bool, noMult
 			},
 		)
 
+		if tenantIDHeader != "" && http.CanonicalHeaderKey(tenantIDHeader) != http.CanonicalHeaderKey(user.OrgIDHeaderName) {
+			config.HTTPMiddleware = append(config.HTTPMiddleware, tenantIDHeaderMiddleware(tenantIDHeader))
+		}
 		return middleware.AuthenticateUser
 	}
 
@@ -69,6 +79,41 @@ func SetupAuthMiddleware(config *server.Config, multitenancyEnabled bool, noMult
 
 }
 
+type internalRequestKey struct{}
+
+// InternalRequestMiddleware marks requests as originated by another Mimir component, so that
+// the tenant ID is read from X-Scope-OrgID even if they're served through the server HTTP
+// middlewares (e.g. by the querier worker when the querier runs without the query-frontend).
+var InternalRequestMiddleware = middleware.Func(func(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), internalRequestKey{}, true)))
+	})
+})
+
+// tenantIDHeaderMiddleware copies the tenant ID from the given HTTP header to the
+// standard X-Scope-OrgID header, so that the rest of the request handling (including
+// the propagation to downstream components) can rely on the standard header.
+// For external requests, the configured header is the only source of the tenant ID: any
+// X-Scope-OrgID header sent by the client is discarded, so requests not carrying the
+// configured header are rejected as unauthenticated.
+func tenantIDHeaderMiddleware(header string) middleware.Interface {
+	return middleware.Func(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if internal, _ := r.Context().Value(internalRequestKey{}).(bool); internal {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if orgID := r.Header.Get(header); orgID != "" {
+				r.Header.Set(user.OrgIDHeaderName, orgID)
+			} else {
+				r.Header.Del(user.OrgIDHeaderName)
+			}
+			next.ServeHTTP(w, r)
+		})
+	})
+}
+
 type serverStream struct {
 	ctx context.Context
 	grpc.ServerStream
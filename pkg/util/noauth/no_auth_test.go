@@ -0,0 +1,169 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package noauth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/gorilla/mux"
+	"github.com/grafana/dskit/middleware"
+	"github.com/grafana/dskit/server"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/mimir/pkg/api"
+	"github.com/grafana/mimir/pkg/frontend/querymiddleware"
+	"github.com/grafana/mimir/pkg/querier/tenantfederation"
+)
+
+func TestSetupAuthMiddleware_HTTP(t *testing.T) {
+	tests := map[string]struct {
+		multitenancyEnabled bool
+		tenantIDHeader      string
+		requestHeaders      map[string]string
+		expectedStatus      int
+		expectedOrgID       string
+	}{
+		"multitenancy disabled should inject the no-auth tenant": {
+			multitenancyEnabled: false,
+			tenantIDHeader:      user.OrgIDHeaderName,
+			expectedStatus:      http.StatusOK,
+			expectedOrgID:       "anonymous",
+		},
+		"multitenancy enabled should read the tenant from X-Scope-OrgID": {
+			multitenancyEnabled: true,
+			tenantIDHeader:      user.OrgIDHeaderName,
+			requestHeaders:      map[string]string{user.OrgIDHeaderName: "user-1"},
+			expectedStatus:      http.StatusOK,
+			expectedOrgID:       "user-1",
+		},
+		"multitenancy enabled should reject requests without tenant": {
+			multitenancyEnabled: true,
+			tenantIDHeader:      user.OrgIDHeaderName,
+			expectedStatus:      http.StatusUnauthorized,
+		},
+		"multitenancy enabled should read the tenant from the custom header": {
+			multitenancyEnabled: true,
+			tenantIDHeader:      "X-Tenant",
+			requestHeaders:      map[string]string{"X-Tenant": "user-1"},
+			expectedStatus:      http.StatusOK,
+			expectedOrgID:       "user-1",
+		},
+		"multitenancy enabled should give precedence to the custom header": {
+			multitenancyEnabled: true,
+			tenantIDHeader:      "X-Tenant",
+			requestHeaders:      map[string]string{"X-Tenant": "user-1", user.OrgIDHeaderName: "user-2"},
+			expectedStatus:      http.StatusOK,
+			expectedOrgID:       "user-1",
+		},
+		"multitenancy enabled should ignore X-Scope-OrgID if the custom header is missing": {
+			multitenancyEnabled: true,
+			tenantIDHeader:      "X-Tenant",
+			requestHeaders:      map[string]string{user.OrgIDHeaderName: "user-2"},
+			expectedStatus:      http.StatusUnauthorized,
+		},
+		"multitenancy enabled should reject requests without the custom header": {
+			multitenancyEnabled: true,
+			tenantIDHeader:      "X-Tenant",
+			expectedStatus:      http.StatusUnauthorized,
+		},
+	}
+
+	for testName, testData := range tests {
+		t.Run(testName, func(t *testing.T) {
+			cfg := &server.Config{}
+			m := SetupAuthMiddleware(cfg, testData.multitenancyEnabled, "anonymous", testData.tenantIDHeader, nil)
+
+			// Simulate a request received by the external HTTP server, which runs the server HTTP
+			// middlewares before the authentication middleware of the route.
+			var actualOrgID string
+			handler := middleware.Merge(append(cfg.HTTPMiddleware, m)...).Wrap(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				orgID, err := user.ExtractOrgID(r.Context())
+				require.NoError(t, err)
+				actualOrgID = orgID
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			for name, value := range testData.requestHeaders {
+				req.Header.Set(name, value)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, testData.expectedStatus, rec.Code)
+			assert.Equal(t, testData.expectedOrgID, actualOrgID)
+		})
+	}
+}
+
+func TestSetupAuthMiddleware_InternalRequests(t *testing.T) {
+	const tenantIDHeader = "X-Tenant"
+
+	cfg := &server.Config{}
+	authMiddleware := SetupAuthMiddleware(cfg, true, "anonymous", tenantIDHeader, nil)
+
+	a, err := api.New(api.Config{HTTPAuthMiddleware: authMiddleware}, tenantfederation.Config{}, *cfg, &server.Server{HTTP: mux.NewRouter()}, log.NewNopLogger())
+	require.NoError(t, err)
+
+	// Build the request the same way the query-frontend does when sending it to the querier.
+	newFrontendRequest := func(t *testing.T) *http.Request {
+		ctx := user.InjectOrgID(context.Background(), "user-1")
+		codec := querymiddleware.NewPrometheusCodec(prometheus.NewPedanticRegistry(), 5*time.Minute, "json")
+		queryExpr, err := parser.ParseExpr("up")
+		require.NoError(t, err)
+
+		req, err := codec.EncodeMetricsQueryRequest(ctx, querymiddleware.NewPrometheusRangeQueryRequest("/api/v1/query_range", 0, 60_000, 15_000, 5*time.Minute, queryExpr, querymiddleware.Options{}, nil))
+		require.NoError(t, err)
+		require.NoError(t, user.InjectOrgIDIntoHTTPRequest(ctx, req))
+		return req
+	}
+
+	var actualOrgID string
+	next := http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		orgID, err := user.ExtractOrgID(r.Context())
+		require.NoError(t, err)
+		actualOrgID = orgID
+	})
+
+	tests := map[string]struct {
+		handler        http.Handler
+		expectedStatus int
+		expectedOrgID  string
+	}{
+		"request served by a router wrapped with the API auth middleware (e.g. httpgrpc or querier internal router)": {
+			handler:        a.AuthMiddleware.Wrap(next),
+			expectedStatus: http.StatusOK,
+			expectedOrgID:  "user-1",
+		},
+		"internal request served through the server HTTP middlewares": {
+			handler:        InternalRequestMiddleware.Wrap(middleware.Merge(cfg.HTTPMiddleware...).Wrap(a.AuthMiddleware.Wrap(next))),
+			expectedStatus: http.StatusOK,
+			expectedOrgID:  "user-1",
+		},
+		"external request served through the server HTTP middlewares": {
+			handler:        middleware.Merge(cfg.HTTPMiddleware...).Wrap(a.AuthMiddleware.Wrap(next)),
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for testName, testData := range tests {
+		t.Run(testName, func(t *testing.T) {
+			actualOrgID = ""
+
+			rec := httptest.NewRecorder()
+			testData.handler.ServeHTTP(rec, newFrontendRequest(t))
+
+			assert.Equal(t, testData.expectedStatus, rec.Code)
+			assert.Equal(t, testData.expectedOrgID, actualOrgID)
+		})
+	}
+}
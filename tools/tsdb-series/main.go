@@ -4,8 +4,10 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"math"
 	"os"
 	"time"
 
@@ -22,12 +24,34 @@ import (
 
 var logger = log.NewLogfmtLogger(os.Stderr)
 
+const (
+	formatText = "text"
+	formatJSON = "json"
+)
+
+// jsonSeries is the JSON representation of a series, printed when -format=json.
+type jsonSeries struct {
+	Labels labels.Labels `json:"labels"`
+	Chunks []jsonChunk   `json:"chunks,omitempty"`
+}
+
+type jsonChunk struct {
+	Ref     chunks.ChunkRef `json:"ref"`
+	MinTime int64           `json:"min_time"`
+	MaxTime int64           `json:"max_time"`
+}
+
 func main() {
 	// Clean up all flags registered via init() methods of 3rd-party libraries.
 	flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ExitOnError)
 
 	metricSelector := flag.String("select", "", "PromQL metric selector")
 	printChunks := flag.Bool("show-chunks", false, "Print chunk details")
+	format := flag.String("format", formatText, fmt.Sprintf("Output format. Supported values: %s, %s. The json format prints one JSON object per series, per line.", formatText, formatJSON))
+
+	var minTime, maxTime flagext.Time
+	flag.Var(&minTime, "min-time", "If set, only chunks with MaxTime >= this value are printed, and series without such chunks are skipped")
+	flag.Var(&maxTime, "max-time", "If set, only chunks with MinTime <= this value are printed, and series without such chunks are skipped")
 
 	// Parse CLI arguments.
 	args, err := flagext.ParseFlagsAndArguments(flag.CommandLine)
@@ -40,8 +64,21 @@ func main() {
 		fmt.Println("No block directory specified.")
 		return
 	}
+
+	if *format != formatText && *format != formatJSON {
+		fmt.Fprintln(os.Stderr, "Unsupported output format:", *format)
+		os.Exit(1)
+	}
 	ctx := context.Background()
 
+	minT, maxT := int64(math.MinInt64), int64(math.MaxInt64)
+	if !time.Time(minTime).IsZero() {
+		minT = timestamp.FromTime(time.Time(minTime))
+	}
+	if !time.Time(maxTime).IsZero() {
+		maxT = timestamp.FromTime(time.Time(maxTime))
+	}
+
 	var matchers []*labels.Matcher
 	if *metricSelector != "" {
 		var err error
@@ -61,11 +98,32 @@ func main() {
 	}
 
 	for _, blockDir := range args {
-		printBlockIndex(ctx, blockDir, *printChunks, matchers)
+		printBlockIndex(ctx, blockDir, *printChunks, *format, minT, maxT, matchers)
 	}
 }
 
-func printBlockIndex(ctx context.Context, blockDir string, printChunks bool, matchers []*labels.Matcher) {
+// filterChunks returns the chunks overlapping the [minT, maxT] time range.
+func filterChunks(chks []chunks.Meta, minT, maxT int64) []chunks.Meta {
+	filtered := chks[:0]
+	for _, c := range chks {
+		if c.MaxTime >= minT && c.MinTime <= maxT {
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered
+}
+
+func newJSONSeries(lbls labels.Labels, chks []chunks.Meta, printChunks bool) jsonSeries {
+	s := jsonSeries{Labels: lbls}
+	if printChunks {
+		for _, c := range chks {
+			s.Chunks = append(s.Chunks, jsonChunk{Ref: c.Ref, MinTime: c.MinTime, MaxTime: c.MaxTime})
+		}
+	}
+	return s
+}
+
+func printBlockIndex(ctx context.Context, blockDir string, printChunks bool, format string, minT, maxT int64, matchers []*labels.Matcher) {
 	block, err := tsdb.OpenBlock(logger, blockDir, nil)
 	if err != nil {
 		level.Error(logger).Log("msg", "failed to open block", "dir", blockDir, "err", err)
@@ -98,6 +156,8 @@ func printBlockIndex(ctx context.Context, blockDir string, printChunks bool, mat
 		return
 	}
 
+	enc := json.NewEncoder(os.Stdout)
+
 	var builder labels.ScratchBuilder
 	for p.Next() {
 		chks := []chunks.Meta(nil)
@@ -121,6 +181,19 @@ func printBlockIndex(ctx context.Context, blockDir string, printChunks bool, mat
 			continue
 		}
 
+		chks = filterChunks(chks, minT, maxT)
+		if len(chks) == 0 {
+			continue
+		}
+
+		if format == formatJSON {
+			if err := enc.Encode(newJSONSeries(lbls, chks, printChunks)); err != nil {
+				level.Error(logger).Log("msg", "failed to encode series", "err", err)
+				return
+			}
+			continue
+		}
+
 		fmt.Println("series", lbls.String())
 		if printChunks {
 			for _, c := range chks {
@@ -0,0 +1,93 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package main
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/tsdb/chunks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONSeries(t *testing.T) {
+	lbls := labels.FromStrings(labels.MetricName, "up", "job", "test")
+	chks := []chunks.Meta{
+		{Ref: 1, MinTime: 10, MaxTime: 20},
+		{Ref: 2, MinTime: 30, MaxTime: 40},
+	}
+
+	tests := map[string]struct {
+		printChunks bool
+		expected    string
+	}{
+		"should omit chunks if printing chunks is disabled": {
+			printChunks: false,
+			expected:    `{"labels":{"__name__":"up","job":"test"}}`,
+		},
+		"should include chunks if printing chunks is enabled": {
+			printChunks: true,
+			expected:    `{"labels":{"__name__":"up","job":"test"},"chunks":[{"ref":1,"min_time":10,"max_time":20},{"ref":2,"min_time":30,"max_time":40}]}`,
+		},
+	}
+
+	for testName, testData := range tests {
+		t.Run(testName, func(t *testing.T) {
+			actual, err := json.Marshal(newJSONSeries(lbls, chks, testData.printChunks))
+			require.NoError(t, err)
+			assert.JSONEq(t, testData.expected, string(actual))
+		})
+	}
+}
+
+func TestFilterChunks(t *testing.T) {
+	tests := map[string]struct {
+		minT, maxT int64
+		expected   []chunks.ChunkRef
+	}{
+		"should keep all chunks if no time range is set": {
+			minT:     math.MinInt64,
+			maxT:     math.MaxInt64,
+			expected: []chunks.ChunkRef{1, 2, 3},
+		},
+		"should keep chunks overlapping the time range": {
+			minT:     20,
+			maxT:     30,
+			expected: []chunks.ChunkRef{1, 2},
+		},
+		"should drop chunks before min time": {
+			minT:     35,
+			maxT:     math.MaxInt64,
+			expected: []chunks.ChunkRef{2, 3},
+		},
+		"should drop chunks after max time": {
+			minT:     math.MinInt64,
+			maxT:     25,
+			expected: []chunks.ChunkRef{1},
+		},
+		"should drop all chunks outside of the time range": {
+			minT:     100,
+			maxT:     200,
+			expected: nil,
+		},
+	}
+
+	for testName, testData := range tests {
+		t.Run(testName, func(t *testing.T) {
+			chks := []chunks.Meta{
+				{Ref: 1, MinTime: 10, MaxTime: 20},
+				{Ref: 2, MinTime: 30, MaxTime: 40},
+				{Ref: 3, MinTime: 50, MaxTime: 60},
+			}
+
+			var actual []chunks.ChunkRef
+			for _, c := range filterChunks(chks, testData.minT, testData.maxT) {
+				actual = append(actual, c.Ref)
+			}
+			assert.Equal(t, testData.expected, actual)
+		})
+	}
+}